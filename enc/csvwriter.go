@@ -0,0 +1,165 @@
+package enc
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// CSVWriter writes tabular data to a writer in comma-separated-value format, quoting fields
+// per RFC 4180 whenever they contain the delimiter, a quote character or a newline.
+type CSVWriter struct {
+	writer         io.Writer
+	rowopen        bool // true if BeginRow was called without a matching EndRow yet
+	fieldsinrow    int  // number of fields written to the currently open row
+	headerswritten bool
+	namemapper     NameMapper // converts struct field names to csv header names; nil means CamelCaseNameMapper
+}
+
+// NewCSVWriter creates a new CSVWriter
+//
+// **Parameters**
+//   - writer: writer to write csv data to
+//
+// **Returns**
+//   - *CSVWriter: created CSVWriter
+func NewCSVWriter(writer io.Writer) *CSVWriter {
+	return &CSVWriter{writer: writer}
+}
+
+// SetNameMapper configures how struct field names are converted to header names by WriteItem,
+// for fields with no overriding `json:"..."` struct tag. The default, used when no mapper has
+// been set, is CamelCaseNameMapper, mirroring JSONWriter.
+//
+// **Parameters**
+//   - mapper: function used to convert Go field names to header names
+//
+// **Returns**
+//   - *CSVWriter: this writer for fluent behavior
+func (writer *CSVWriter) SetNameMapper(mapper NameMapper) *CSVWriter {
+	writer.namemapper = mapper
+	return writer
+}
+
+func (writer *CSVWriter) mapName(name string) string {
+	if writer.namemapper != nil {
+		return writer.namemapper(name)
+	}
+	return CamelCaseNameMapper(name)
+}
+
+func (writer *CSVWriter) escape(value string) string {
+	if !strings.ContainsAny(value, ",\"\r\n") {
+		return value
+	}
+	return "\"" + strings.ReplaceAll(value, "\"", "\"\"") + "\""
+}
+
+// BeginRow starts a new row. The previously started row, if any, must already have been closed
+// with EndRow.
+//
+// **Returns**
+//   - *CSVWriter: this writer for fluent behavior
+func (writer *CSVWriter) BeginRow() *CSVWriter {
+	if writer.rowopen {
+		panic("Tried to begin a row while another row is still open")
+	}
+
+	writer.rowopen = true
+	writer.fieldsinrow = 0
+	return writer
+}
+
+// WriteField writes a single field to the currently open row, quoting it if necessary
+//
+// **Parameters**
+//   - value: field value to write
+//
+// **Returns**
+//   - *CSVWriter: this writer for fluent behavior
+func (writer *CSVWriter) WriteField(value string) *CSVWriter {
+	if !writer.rowopen {
+		panic("Tried to write a field outside of a row")
+	}
+
+	if writer.fieldsinrow > 0 {
+		io.WriteString(writer.writer, ",")
+	}
+	io.WriteString(writer.writer, writer.escape(value))
+	writer.fieldsinrow++
+	return writer
+}
+
+// EndRow closes the currently open row
+//
+// **Returns**
+//   - *CSVWriter: this writer for fluent behavior
+func (writer *CSVWriter) EndRow() *CSVWriter {
+	if !writer.rowopen {
+		panic("Tried to end a row that wasn't open")
+	}
+
+	io.WriteString(writer.writer, "\r\n")
+	writer.rowopen = false
+	return writer
+}
+
+// WriteItem writes v, a struct or pointer to one, as a csv row, deriving one field per
+// exported field using the same `json:"..."` struct tag and name-mapping rules as JSONWriter's
+// reflection path (the "omitempty" option is ignored, since every row of a csv document must
+// have the same number of columns). Before the first record, a header row is written
+// automatically, derived from the same field names.
+//
+// **Parameters**
+//   - v: value to serialize
+//
+// **Returns**
+//   - *CSVWriter: this writer for fluent behavior
+func (writer *CSVWriter) WriteItem(v interface{}) *CSVWriter {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	t := value.Type()
+
+	if !writer.headerswritten {
+		writer.BeginRow()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported field
+			}
+
+			tag := parseJSONTag(field)
+			if tag.skip {
+				continue
+			}
+
+			name := tag.name
+			if name == "" {
+				name = writer.mapName(field.Name)
+			}
+			writer.WriteField(name)
+		}
+		writer.EndRow()
+		writer.headerswritten = true
+	}
+
+	writer.BeginRow()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported field
+		}
+
+		tag := parseJSONTag(field)
+		if tag.skip {
+			continue
+		}
+
+		writer.WriteField(fmt.Sprintf("%v", value.Field(i).Interface()))
+	}
+	writer.EndRow()
+	return writer
+}
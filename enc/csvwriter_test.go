@@ -0,0 +1,52 @@
+package enc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSVRows(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewCSVWriter(&buffer)
+
+	writer.BeginRow()
+	writer.WriteField("name")
+	writer.WriteField("age")
+	writer.EndRow()
+
+	writer.BeginRow()
+	writer.WriteField("Diether")
+	writer.WriteField("35")
+	writer.EndRow()
+
+	assert.Equal(t, "name,age\r\nDiether,35\r\n", buffer.String())
+}
+
+func TestWriteCSVFieldEscapesSpecialCharacters(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewCSVWriter(&buffer)
+
+	writer.BeginRow()
+	writer.WriteField(`Boffel, "Diether"`)
+	writer.EndRow()
+
+	assert.Equal(t, "\"Boffel, \"\"Diether\"\"\"\r\n", buffer.String())
+}
+
+type csvTestPerson struct {
+	Name   string
+	Number int
+	Secret string `json:"-"`
+}
+
+func TestWriteItemDerivesHeaderFromStruct(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewCSVWriter(&buffer)
+
+	writer.WriteItem(&csvTestPerson{Name: "Diether", Number: 35, Secret: "hidden"})
+	writer.WriteItem(&csvTestPerson{Name: "Boffel", Number: 12, Secret: "hidden"})
+
+	assert.Equal(t, "name,number\r\nDiether,35\r\nBoffel,12\r\n", buffer.String())
+}
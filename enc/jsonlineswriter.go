@@ -0,0 +1,67 @@
+package enc
+
+import "io"
+
+// JSONLinesWriter writes a stream of json values to a writer, one per line, in the
+// newline-delimited json ("json lines" / ndjson) format. It reuses JSONWriter's state machine
+// to write each value, resetting it between records so every line is once again a valid,
+// independent top-level json value.
+type JSONLinesWriter struct {
+	target io.Writer
+	writer *JSONWriter
+}
+
+// NewJSONLinesWriter creates a new JSONLinesWriter
+//
+// **Parameters**
+//   - writer: writer to write json-lines data to
+//
+// **Returns**
+//   - *JSONLinesWriter: created JSONLinesWriter
+func NewJSONLinesWriter(writer io.Writer) *JSONLinesWriter {
+	return &JSONLinesWriter{
+		target: writer,
+		writer: NewJSONWriter(writer)}
+}
+
+// SetNameMapper configures how struct field names are converted to json property names for
+// every subsequent record. See JSONWriter.SetNameMapper.
+//
+// **Parameters**
+//   - mapper: function used to convert Go field names to json property names
+//
+// **Returns**
+//   - *JSONLinesWriter: this writer for fluent behavior
+func (writer *JSONLinesWriter) SetNameMapper(mapper NameMapper) *JSONLinesWriter {
+	writer.writer.SetNameMapper(mapper)
+	return writer
+}
+
+// SetEmitNull controls how the reflection-based WriteItem path of every subsequent record
+// treats nil struct fields. See JSONWriter.SetEmitNull.
+//
+// **Parameters**
+//   - emit: true to emit explicit null for nil fields, false to omit them
+//
+// **Returns**
+//   - *JSONLinesWriter: this writer for fluent behavior
+func (writer *JSONLinesWriter) SetEmitNull(emit bool) *JSONLinesWriter {
+	writer.writer.SetEmitNull(emit)
+	return writer
+}
+
+// WriteItem writes item as a single line of json, terminated with a newline, then resets the
+// underlying JSONWriter so the next call starts a fresh top-level value
+//
+// **Parameters**
+//   - item: item to write
+//
+// **Returns**
+//   - *JSONLinesWriter: this writer for fluent behavior
+func (writer *JSONLinesWriter) WriteItem(item interface{}) *JSONLinesWriter {
+	writer.writer.WriteItem(item)
+	writer.writer.Close()
+	io.WriteString(writer.target, "\n")
+	writer.writer.reset()
+	return writer
+}
@@ -0,0 +1,28 @@
+package enc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJSONLines(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONLinesWriter(&buffer)
+
+	writer.WriteItem(&TestData{Name: "Diether", Number: 35})
+	writer.WriteItem(&TestData{Name: "Boffel", Number: 12})
+
+	assert.Equal(t, "{\"name\":\"Diether\",\"number\":35}\n{\"name\":\"Boffel\",\"number\":12}\n", buffer.String())
+}
+
+func TestWriteJSONLinesWithScalarItems(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONLinesWriter(&buffer)
+
+	writer.WriteItem("first")
+	writer.WriteItem(42)
+
+	assert.Equal(t, "\"first\"\n42\n", buffer.String())
+}
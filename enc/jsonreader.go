@@ -0,0 +1,607 @@
+package enc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// DefaultJSONMaxDepth is the nesting depth a JSONReader created via NewJSONReader will accept
+// before failing with an error. Use NewJSONReaderDepth to configure a different limit.
+const DefaultJSONMaxDepth = 10000
+
+// JSONReaderError describes an error encountered while reading json, including the byte
+// offset into the input stream at which the error was detected.
+type JSONReaderError struct {
+	Offset  int64  // byte offset into the input stream
+	Message string // description of the error
+}
+
+// Error returns a human readable description of the error, including its byte offset
+//
+// **Returns**
+//   - string: description of the error
+func (err *JSONReaderError) Error() string {
+	return fmt.Sprintf("%s (offset %d)", err.Message, err.Offset)
+}
+
+// JSONReader reads json data from a reader in a pull/streaming fashion, mirroring the
+// structural events produced by JSONWriter. Only the value currently being read is held in
+// memory, so documents of arbitrary size can be processed directly from the underlying
+// io.Reader as long as nesting stays within maxdepth.
+type JSONReader struct {
+	decoder    *json.Decoder
+	stack      []JSONState // currently open JSONStateObject/JSONStateArray structures
+	maxdepth   int
+	pending    json.Token // token read ahead of time by Peek
+	haspending bool
+	namemapper NameMapper // converts json property names to struct field names; nil means CamelCaseNameMapper
+}
+
+// NewJSONReader creates a new JSONReader bounded by DefaultJSONMaxDepth
+//
+// **Parameters**
+//   - reader: reader to read json data from
+//
+// **Returns**
+//   - *JSONReader: created JSONReader
+func NewJSONReader(reader io.Reader) *JSONReader {
+	return NewJSONReaderDepth(reader, DefaultJSONMaxDepth)
+}
+
+// NewJSONReaderDepth creates a new JSONReader which fails with an error once the document
+// nests deeper than maxdepth, guarding against unbounded memory growth on malicious input.
+//
+// **Parameters**
+//   - reader:   reader to read json data from
+//   - maxdepth: maximum nesting depth to accept
+//
+// **Returns**
+//   - *JSONReader: created JSONReader
+func NewJSONReaderDepth(reader io.Reader, maxdepth int) *JSONReader {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	return &JSONReader{
+		decoder:  decoder,
+		maxdepth: maxdepth}
+}
+
+// SetNameMapper configures how json property names are matched against struct field names by
+// Decode, for fields with no overriding `json:"..."` struct tag. The mapper is applied to each
+// candidate Go field name and compared against the property name read from the input; it
+// should be the same mapper passed to JSONWriter.SetNameMapper when writing the document, so
+// round-tripping through both sides agrees on names. The default, used when no mapper has been
+// set, is CamelCaseNameMapper.
+//
+// **Parameters**
+//   - mapper: function used to convert Go field names to json property names
+//
+// **Returns**
+//   - *JSONReader: this reader for fluent behavior
+func (reader *JSONReader) SetNameMapper(mapper NameMapper) *JSONReader {
+	reader.namemapper = mapper
+	return reader
+}
+
+func (reader *JSONReader) mapName(name string) string {
+	if reader.namemapper != nil {
+		return reader.namemapper(name)
+	}
+	return CamelCaseNameMapper(name)
+}
+
+func (reader *JSONReader) errorf(format string, args ...interface{}) error {
+	return &JSONReaderError{
+		Offset:  reader.decoder.InputOffset(),
+		Message: fmt.Sprintf(format, args...)}
+}
+
+func (reader *JSONReader) nextToken() (json.Token, error) {
+	if reader.haspending {
+		reader.haspending = false
+		return reader.pending, nil
+	}
+
+	token, err := reader.decoder.Token()
+	if err != nil {
+		return nil, reader.errorf("%s", err.Error())
+	}
+	return token, nil
+}
+
+func (reader *JSONReader) peekToken() (json.Token, error) {
+	if !reader.haspending {
+		token, err := reader.decoder.Token()
+		if err != nil {
+			return nil, reader.errorf("%s", err.Error())
+		}
+
+		reader.pending = token
+		reader.haspending = true
+	}
+
+	return reader.pending, nil
+}
+
+func (reader *JSONReader) top() (JSONState, bool) {
+	if len(reader.stack) == 0 {
+		return JSONStateNone, false
+	}
+
+	return reader.stack[len(reader.stack)-1], true
+}
+
+func (reader *JSONReader) push(state JSONState) error {
+	if len(reader.stack) >= reader.maxdepth {
+		return reader.errorf("json document exceeds maximum depth of %d", reader.maxdepth)
+	}
+
+	reader.stack = append(reader.stack, state)
+	return nil
+}
+
+// Peek reports the JSONState of the upcoming value without consuming it: JSONStateObject or
+// JSONStateArray if the next token opens a new structure, JSONStateNone if a scalar item or
+// the closing delimiter of the current structure follows. Call HasNext to find out whether
+// the current object/array holds another element before relying on Peek to classify it.
+//
+// **Returns**
+//   - JSONState: state describing the upcoming token
+//   - error: error if any occurred
+func (reader *JSONReader) Peek() (JSONState, error) {
+	token, err := reader.peekToken()
+	if err != nil {
+		return JSONStateNone, err
+	}
+
+	if delim, ok := token.(json.Delim); ok {
+		switch delim {
+		case '{':
+			return JSONStateObject, nil
+		case '[':
+			return JSONStateArray, nil
+		}
+	}
+
+	return JSONStateNone, nil
+}
+
+// HasNext reports whether the object or array currently being read holds another key or
+// item before its closing delimiter. Call this before ReadKey in an object, or before
+// reading the next element of an array.
+//
+// **Returns**
+//   - bool: true if another key/item follows, false if the structure is about to end
+func (reader *JSONReader) HasNext() bool {
+	return reader.decoder.More()
+}
+
+// BeginObject reads the opening token of a json object
+//
+// **Returns**
+//   - error: error if the next token is not the start of an object, or maxdepth is exceeded
+func (reader *JSONReader) BeginObject() error {
+	token, err := reader.nextToken()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim('{') {
+		return reader.errorf("expected start of object, found %v", token)
+	}
+
+	return reader.push(JSONStateObject)
+}
+
+// EndObject reads the closing token of the currently open json object
+//
+// **Returns**
+//   - error: error if the reader is not inside an object, or the next token is not its end
+func (reader *JSONReader) EndObject() error {
+	state, ok := reader.top()
+	if !ok || state != JSONStateObject {
+		return reader.errorf("not currently inside an object")
+	}
+
+	token, err := reader.nextToken()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim('}') {
+		return reader.errorf("expected end of object, found %v", token)
+	}
+
+	reader.stack = reader.stack[:len(reader.stack)-1]
+	return nil
+}
+
+// BeginArray reads the opening token of a json array
+//
+// **Returns**
+//   - error: error if the next token is not the start of an array, or maxdepth is exceeded
+func (reader *JSONReader) BeginArray() error {
+	token, err := reader.nextToken()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim('[') {
+		return reader.errorf("expected start of array, found %v", token)
+	}
+
+	return reader.push(JSONStateArray)
+}
+
+// EndArray reads the closing token of the currently open json array
+//
+// **Returns**
+//   - error: error if the reader is not inside an array, or the next token is not its end
+func (reader *JSONReader) EndArray() error {
+	state, ok := reader.top()
+	if !ok || state != JSONStateArray {
+		return reader.errorf("not currently inside an array")
+	}
+
+	token, err := reader.nextToken()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim(']') {
+		return reader.errorf("expected end of array, found %v", token)
+	}
+
+	reader.stack = reader.stack[:len(reader.stack)-1]
+	return nil
+}
+
+// ReadKey reads the key of the next property of the currently open json object
+//
+// **Returns**
+//   - string: key that was read
+//   - error: error if the reader is not inside an object, or the next token is not a key
+func (reader *JSONReader) ReadKey() (string, error) {
+	state, ok := reader.top()
+	if !ok || state != JSONStateObject {
+		return "", reader.errorf("not currently inside an object")
+	}
+
+	token, err := reader.nextToken()
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := token.(string)
+	if !ok {
+		return "", reader.errorf("expected a property key, found %v", token)
+	}
+
+	return key, nil
+}
+
+// ReadItem reads the next scalar value (string, number, bool or null) from the stream.
+// Numbers are returned as json.Number so callers can decide how to parse them. Use Peek
+// beforehand to find out whether the upcoming value is an object or array instead.
+//
+// **Returns**
+//   - interface{}: the value that was read, or nil for json null
+//   - error: error if any occurred, including finding an object or array instead of a scalar
+func (reader *JSONReader) ReadItem() (interface{}, error) {
+	token, err := reader.nextToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := token.(json.Delim); ok {
+		return nil, reader.errorf("expected a scalar value, found %v", token)
+	}
+
+	return token, nil
+}
+
+// structFieldsByName maps the json property name of every exported field of t to its field
+// index, honoring the same `json:"..."` struct tags (name override, "-" to skip) that
+// JSONWriter.writeObject honors, and falling back to the reader's NameMapper (CamelCaseNameMapper
+// by default) for fields with no overriding tag.
+func (reader *JSONReader) structFieldsByName(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := parseJSONTag(field)
+		if tag.skip {
+			continue
+		}
+
+		name := tag.name
+		if name == "" {
+			name = reader.mapName(field.Name)
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+func assignScalar(target reflect.Value, item interface{}) error {
+	if item == nil {
+		switch target.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			target.Set(reflect.Zero(target.Type()))
+		}
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if number, ok := item.(json.Number); ok {
+			if value, err := number.Float64(); err == nil {
+				target.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+		target.Set(reflect.ValueOf(item))
+	case reflect.String:
+		value, ok := item.(string)
+		if !ok {
+			return fmt.Errorf("cannot assign %T to a string field", item)
+		}
+		target.SetString(value)
+	case reflect.Bool:
+		value, ok := item.(bool)
+		if !ok {
+			return fmt.Errorf("cannot assign %T to a bool field", item)
+		}
+		target.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := item.(json.Number)
+		if !ok {
+			return fmt.Errorf("cannot assign %T to a numeric field", item)
+		}
+		value, err := number.Int64()
+		if err != nil {
+			return err
+		}
+		target.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := item.(json.Number)
+		if !ok {
+			return fmt.Errorf("cannot assign %T to a numeric field", item)
+		}
+		value, err := number.Int64()
+		if err != nil {
+			return err
+		}
+		target.SetUint(uint64(value))
+	case reflect.Float32, reflect.Float64:
+		number, ok := item.(json.Number)
+		if !ok {
+			return fmt.Errorf("cannot assign %T to a numeric field", item)
+		}
+		value, err := number.Float64()
+		if err != nil {
+			return err
+		}
+		target.SetFloat(value)
+	default:
+		return fmt.Errorf("unsupported field kind %s", target.Kind())
+	}
+	return nil
+}
+
+// convertMapKey converts a json object key, always a string, to keytype, the key type of the
+// map being decoded into, mirroring the key kinds encoding/json supports for map keys: string,
+// integer kinds parsed as base-10, and bool. Any other key kind is rejected with an error
+// instead of panicking inside reflect.Value.SetMapIndex.
+func convertMapKey(key string, keytype reflect.Type) (reflect.Value, error) {
+	switch keytype.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(keytype), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot use json key %q as a %s map key: %s", key, keytype, err.Error())
+		}
+		value := reflect.New(keytype).Elem()
+		value.SetInt(parsed)
+		return value, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot use json key %q as a %s map key: %s", key, keytype, err.Error())
+		}
+		value := reflect.New(keytype).Elem()
+		value.SetUint(parsed)
+		return value, nil
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(key)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot use json key %q as a bool map key: %s", key, err.Error())
+		}
+		return reflect.ValueOf(parsed), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported map key kind %s", keytype.Kind())
+	}
+}
+
+func (reader *JSONReader) decodeObject(target reflect.Value) error {
+	if err := reader.BeginObject(); err != nil {
+		return err
+	}
+
+	var fields map[string]int
+	switch target.Kind() {
+	case reflect.Struct:
+		fields = reader.structFieldsByName(target.Type())
+	case reflect.Map:
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+	}
+
+	for reader.HasNext() {
+		key, err := reader.ReadKey()
+		if err != nil {
+			return err
+		}
+
+		switch target.Kind() {
+		case reflect.Struct:
+			index, ok := fields[key]
+			if !ok {
+				if err := reader.skipValue(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := reader.decodeValue(target.Field(index)); err != nil {
+				return err
+			}
+		case reflect.Map:
+			mapkey, err := convertMapKey(key, target.Type().Key())
+			if err != nil {
+				return reader.errorf("%s", err.Error())
+			}
+
+			element := reflect.New(target.Type().Elem()).Elem()
+			if err := reader.decodeValue(element); err != nil {
+				return err
+			}
+			target.SetMapIndex(mapkey, element)
+		default:
+			if err := reader.skipValue(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return reader.EndObject()
+}
+
+func (reader *JSONReader) decodeSlice(target reflect.Value) error {
+	if err := reader.BeginArray(); err != nil {
+		return err
+	}
+
+	elementtype := target.Type().Elem()
+	result := reflect.MakeSlice(target.Type(), 0, 0)
+	for reader.HasNext() {
+		element := reflect.New(elementtype).Elem()
+		if err := reader.decodeValue(element); err != nil {
+			return err
+		}
+		result = reflect.Append(result, element)
+	}
+
+	if err := reader.EndArray(); err != nil {
+		return err
+	}
+
+	target.Set(result)
+	return nil
+}
+
+// decodeArray decodes a json array into target, a fixed-size Go array, writing elements
+// directly into target.Index(i) instead of growing a slice. Elements beyond target's length
+// are read and discarded, mirroring encoding/json's handling of an array literal that is
+// longer than the Go array it decodes into.
+func (reader *JSONReader) decodeArray(target reflect.Value) error {
+	if err := reader.BeginArray(); err != nil {
+		return err
+	}
+
+	index := 0
+	for reader.HasNext() {
+		if index < target.Len() {
+			if err := reader.decodeValue(target.Index(index)); err != nil {
+				return err
+			}
+		} else if err := reader.skipValue(); err != nil {
+			return err
+		}
+		index++
+	}
+
+	return reader.EndArray()
+}
+
+func (reader *JSONReader) decodeValue(target reflect.Value) error {
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	state, err := reader.Peek()
+	if err != nil {
+		return err
+	}
+
+	switch state {
+	case JSONStateObject:
+		if target.Kind() == reflect.Interface {
+			generic := reflect.ValueOf(map[string]interface{}{})
+			if err := reader.decodeObject(generic); err != nil {
+				return err
+			}
+			target.Set(generic)
+			return nil
+		}
+		return reader.decodeObject(target)
+	case JSONStateArray:
+		if target.Kind() == reflect.Interface {
+			var generic []interface{}
+			slice := reflect.ValueOf(&generic).Elem()
+			if err := reader.decodeSlice(slice); err != nil {
+				return err
+			}
+			target.Set(slice)
+			return nil
+		}
+		if target.Kind() == reflect.Array {
+			return reader.decodeArray(target)
+		}
+		return reader.decodeSlice(target)
+	default:
+		item, err := reader.ReadItem()
+		if err != nil {
+			return err
+		}
+		return assignScalar(target, item)
+	}
+}
+
+// skipValue reads and discards the next value, regardless of its shape
+func (reader *JSONReader) skipValue() error {
+	var discard interface{}
+	return reader.decodeValue(reflect.ValueOf(&discard).Elem())
+}
+
+// Decode reads a single json value from the stream into v, which must be a non-nil pointer.
+// Object keys are matched against struct fields using the same `json:"..."` struct tag and
+// NameMapper conventions as JSONWriter.writeObject: a `json:"full_name"` tag matches the key
+// "full_name" regardless of the field's Go name, and an untagged exported field named Name is
+// matched via the reader's NameMapper (CamelCaseNameMapper by default), so "name" is read into
+// it. Use SetNameMapper to match whatever mapper was used to write the document. Fields
+// present in the json input but not found on the target struct are skipped.
+//
+// **Parameters**
+//   - v: pointer to the value to decode into
+//
+// **Returns**
+//   - error: error if any occurred, including malformed input or maxdepth being exceeded
+func (reader *JSONReader) Decode(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return reader.errorf("Decode requires a non-nil pointer, got %T", v)
+	}
+
+	return reader.decodeValue(value.Elem())
+}
@@ -0,0 +1,185 @@
+package enc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ReaderTestData struct {
+	Name   string
+	Number int
+	Tags   []string
+}
+
+func TestReadValidJson(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"firstname":"Diether","lastname":"Boffel","age":35}`))
+
+	assert.NoError(t, reader.BeginObject())
+
+	key, err := reader.ReadKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "firstname", key)
+	value, err := reader.ReadItem()
+	assert.NoError(t, err)
+	assert.Equal(t, "Diether", value)
+
+	key, err = reader.ReadKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "lastname", key)
+	value, err = reader.ReadItem()
+	assert.NoError(t, err)
+	assert.Equal(t, "Boffel", value)
+
+	key, err = reader.ReadKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "age", key)
+	number, err := reader.ReadItem()
+	assert.NoError(t, err)
+	assert.Equal(t, json.Number("35"), number)
+
+	assert.NoError(t, reader.EndObject())
+}
+
+func TestPeekReportsUpcomingStructure(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"items":[1,2],"done":true}`))
+	assert.NoError(t, reader.BeginObject())
+
+	key, err := reader.ReadKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "items", key)
+
+	state, err := reader.Peek()
+	assert.NoError(t, err)
+	assert.Equal(t, JSONStateArray, state)
+
+	assert.NoError(t, reader.BeginArray())
+	assert.True(t, reader.HasNext())
+	for reader.HasNext() {
+		_, err := reader.ReadItem()
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, reader.EndArray())
+}
+
+func TestReadKeyOutsideObjectFails(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`[1,2]`))
+	assert.NoError(t, reader.BeginArray())
+	_, err := reader.ReadKey()
+	assert.Error(t, err)
+}
+
+func TestDecodeIntoStruct(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"name":"Test","number":8,"tags":["a","b"],"extra":"ignored"}`))
+
+	var data ReaderTestData
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Test", data.Name)
+	assert.Equal(t, 8, data.Number)
+	assert.Equal(t, []string{"a", "b"}, data.Tags)
+}
+
+func TestDecodeIntoGenericInterface(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"a":1,"b":[true,null]}`))
+
+	var data interface{}
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	object, ok := data.(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), object["a"])
+
+	list, ok := object["b"].([]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, true, list[0])
+	assert.Nil(t, list[1])
+}
+
+type ReaderTaggedTestData struct {
+	FullName string `json:"full_name"`
+	Hidden   string `json:"-"`
+}
+
+func TestDecodeHonorsJsonTags(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"full_name":"Alice","hidden":"leaked"}`))
+
+	var data ReaderTaggedTestData
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", data.FullName)
+	assert.Equal(t, "", data.Hidden)
+}
+
+func TestDecodeWithSnakeCaseNameMapper(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"name":"Test","number":8}`))
+	reader.SetNameMapper(SnakeCaseNameMapper)
+
+	var data ReaderTestData
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Test", data.Name)
+	assert.Equal(t, 8, data.Number)
+}
+
+func TestDecodeIntoFixedSizeArray(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`["a","b"]`))
+
+	var data [2]string
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [2]string{"a", "b"}, data)
+}
+
+func TestDecodeIntoFixedSizeArrayDiscardsExtraElements(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`["a","b","c"]`))
+
+	var data [2]string
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [2]string{"a", "b"}, data)
+}
+
+func TestDecodeIntoMapWithIntKeys(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"1":"a","2":"b"}`))
+
+	var data map[int]string
+	err := reader.Decode(&data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "a", 2: "b"}, data)
+}
+
+func TestDecodeIntoMapWithUnsupportedKeyFails(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"a":1}`))
+
+	var data map[float64]int
+	err := reader.Decode(&data)
+
+	assert.Error(t, err)
+}
+
+func TestDecodeRejectsNonPointer(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{}`))
+
+	var data ReaderTestData
+	err := reader.Decode(data)
+	assert.Error(t, err)
+}
+
+func TestMaxDepthExceeded(t *testing.T) {
+	reader := NewJSONReaderDepth(strings.NewReader(`[[[1]]]`), 2)
+
+	assert.NoError(t, reader.BeginArray())
+	assert.NoError(t, reader.BeginArray())
+	err := reader.BeginArray()
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"unicode"
+	"unicode/utf16"
 )
 
 // JSONState current state of JSON writer
@@ -38,6 +39,12 @@ type JSONWriter struct {
 	trailingseparator bool      // flag used to determine whether a separator is to be written before starting a new item
 	structure         int64
 	depth             int
+	indentprefix      string     // prefix written at the start of every indented line
+	indentstring      string     // string repeated once per nesting level to indent a line
+	level             int        // current object/array nesting level, used to compute indentation
+	bracketopen       bool       // true if the innermost object/array was just opened and has no members yet
+	namemapper        NameMapper // converts struct field names to json property names; nil means CamelCaseNameMapper
+	emitnull          bool       // if true, the reflection path writes null for nil fields instead of omitting them
 }
 
 // NewJSONWriter creates a new JSONWriter
@@ -55,6 +62,38 @@ func NewJSONWriter(writer io.Writer) *JSONWriter {
 		depth:             0}
 }
 
+// SetIndent instructs the writer to pretty-print its output, matching the semantics of
+// encoding/json's Indent/MarshalIndent. Each element in a nested structure begins on a new
+// line, prefixed with prefix and indented by one copy of indent per nesting level. Passing
+// empty strings for both arguments (the default) restores minified, single-line output.
+//
+// **Parameters**
+//   - prefix: string written at the beginning of each line
+//   - indent: string repeated per nesting level to indent that line
+//
+// **Returns**
+//   - *JSONWriter: this writer for fluent behavior
+func (writer *JSONWriter) SetIndent(prefix string, indent string) *JSONWriter {
+	writer.indentprefix = prefix
+	writer.indentstring = indent
+	return writer
+}
+
+// pretty determines whether indented output was requested via SetIndent
+func (writer *JSONWriter) pretty() bool {
+	return writer.indentprefix != "" || writer.indentstring != ""
+}
+
+// writeNewline writes a newline followed by the configured prefix and indentation for the
+// given nesting level. It is a no-op unless SetIndent was used to enable pretty-printing.
+func (writer *JSONWriter) writeNewline(level int) {
+	if !writer.pretty() {
+		return
+	}
+
+	io.WriteString(writer.writer, "\n"+writer.indentprefix+strings.Repeat(writer.indentstring, level))
+}
+
 // JSONStateName converts a JSONState to a readable string
 //
 // **Parameters**
@@ -102,7 +141,11 @@ func (writer *JSONWriter) begin(jsontype JSONState) {
 	if writer.trailingseparator {
 		io.WriteString(writer.writer, ",")
 		writer.trailingseparator = false
+		writer.writeNewline(writer.level)
+	} else if writer.bracketopen {
+		writer.writeNewline(writer.level)
 	}
+	writer.bracketopen = false
 }
 
 func (writer *JSONWriter) end() {
@@ -125,15 +168,29 @@ func (writer *JSONWriter) escape(value string) string {
 	for _, char := range value {
 		switch char {
 		default:
-			if char < 0x20 {
-				builder.WriteString(fmt.Sprintf("\\u%04d", char))
-			} else {
+			switch {
+			case char < 0x20:
+				builder.WriteString(fmt.Sprintf("\\u%04x", char))
+			case char > 0xFFFF:
+				high, low := utf16.EncodeRune(char)
+				builder.WriteString(fmt.Sprintf("\\u%04x\\u%04x", high, low))
+			default:
 				builder.WriteRune(char)
 			}
 		case '\\':
 			builder.WriteString("\\\\")
 		case '"':
 			builder.WriteString("\\\"")
+		case '\b':
+			builder.WriteString("\\b")
+		case '\f':
+			builder.WriteString("\\f")
+		case '\n':
+			builder.WriteString("\\n")
+		case '\r':
+			builder.WriteString("\\r")
+		case '\t':
+			builder.WriteString("\\t")
 		}
 	}
 	builder.WriteString("\"")
@@ -147,6 +204,8 @@ func (writer *JSONWriter) escape(value string) string {
 func (writer *JSONWriter) BeginObject() *JSONWriter {
 	writer.begin(JSONStateObject)
 	io.WriteString(writer.writer, "{")
+	writer.level++
+	writer.bracketopen = true
 	return writer
 }
 
@@ -155,6 +214,13 @@ func (writer *JSONWriter) BeginObject() *JSONWriter {
 // **Returns**
 //   - *JSONWriter: created JSONWriter
 func (writer *JSONWriter) EndObject() *JSONWriter {
+	empty := writer.bracketopen
+	writer.level--
+	if !empty {
+		writer.writeNewline(writer.level)
+	}
+	writer.bracketopen = false
+
 	writer.end()
 	io.WriteString(writer.writer, "}")
 	writer.trailingseparator = true
@@ -168,6 +234,8 @@ func (writer *JSONWriter) EndObject() *JSONWriter {
 func (writer *JSONWriter) BeginArray() *JSONWriter {
 	writer.begin(JSONStateArray)
 	io.WriteString(writer.writer, "[")
+	writer.level++
+	writer.bracketopen = true
 	return writer
 }
 
@@ -176,6 +244,13 @@ func (writer *JSONWriter) BeginArray() *JSONWriter {
 // **Returns**
 //   - *JSONWriter: created JSONWriter
 func (writer *JSONWriter) EndArray() *JSONWriter {
+	empty := writer.bracketopen
+	writer.level--
+	if !empty {
+		writer.writeNewline(writer.level)
+	}
+	writer.bracketopen = false
+
 	writer.end()
 	io.WriteString(writer.writer, "]")
 	writer.trailingseparator = true
@@ -191,7 +266,11 @@ func (writer *JSONWriter) EndArray() *JSONWriter {
 //   - *JSONWriter: created JSONWriter
 func (writer *JSONWriter) WriteKey(key string) *JSONWriter {
 	writer.begin(JSONStateKey)
-	io.WriteString(writer.writer, fmt.Sprintf("%s:", writer.escape(key)))
+	if writer.pretty() {
+		io.WriteString(writer.writer, fmt.Sprintf("%s: ", writer.escape(key)))
+	} else {
+		io.WriteString(writer.writer, fmt.Sprintf("%s:", writer.escape(key)))
+	}
 	return writer
 }
 
@@ -214,6 +293,22 @@ func (writer *JSONWriter) WriteProperty(name string, value interface{}) *JSONWri
 	return writer
 }
 
+// WriteNullableProperty writes a property of a json object, writing an explicit json null
+// when value is nil instead of silently omitting the property like WriteProperty does. Use
+// this when consumers need to distinguish an absent property from one explicitly set to null.
+//
+// **Parameters**
+//   - name : name of the property
+//   - value: property value
+//
+// **Returns**
+//   - *JSONWriter: created JSONWriter
+func (writer *JSONWriter) WriteNullableProperty(name string, value interface{}) *JSONWriter {
+	writer.WriteKey(name)
+	writer.WriteItem(value)
+	return writer
+}
+
 // Close closes all open structures of the json writer
 func (writer *JSONWriter) Close() {
 	for writer.depth > 0 {
@@ -230,21 +325,171 @@ func (writer *JSONWriter) Close() {
 	}
 }
 
-func (writer *JSONWriter) toCamelCase(data string) string {
-	if len(data) == 0 {
-		return data
+// NameMapper converts a struct field's Go name to the name used when writing it as a json
+// property key. Used by JSONWriter's reflection-based WriteItem/writeObject path whenever a
+// field has no overriding `json:"..."` struct tag.
+type NameMapper func(name string) string
+
+// CamelCaseNameMapper converts a Go field name to camelCase by lowercasing its first rune,
+// e.g. "FirstName" becomes "firstName". This is the mapper used when no other mapper has
+// been set via SetNameMapper.
+func CamelCaseNameMapper(name string) string {
+	if len(name) == 0 {
+		return name
 	}
 
 	var firstrune rune
-	for _, firstrune = range data {
+	for _, firstrune = range name {
 		break
 	}
 
 	if unicode.IsLower(firstrune) {
-		return data
+		return name
+	}
+
+	return string(unicode.ToLower(firstrune)) + name[len(string(firstrune)):]
+}
+
+// SnakeCaseNameMapper converts a Go field name to snake_case, e.g. "FirstName" becomes
+// "first_name".
+func SnakeCaseNameMapper(name string) string {
+	var builder strings.Builder
+	for i, char := range name {
+		if unicode.IsUpper(char) {
+			if i > 0 {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(char))
+		} else {
+			builder.WriteRune(char)
+		}
 	}
+	return builder.String()
+}
 
-	return string(unicode.ToLower(firstrune)) + data[1:]
+// PascalCaseNameMapper returns the Go field name unchanged, since exported field names are
+// already PascalCase.
+func PascalCaseNameMapper(name string) string {
+	return name
+}
+
+// VerbatimNameMapper returns the Go field name unchanged, without applying any case
+// conversion at all.
+func VerbatimNameMapper(name string) string {
+	return name
+}
+
+// SetNameMapper configures how struct field names are converted to json property names by
+// the reflection-based WriteItem path, for fields with no overriding `json:"..."` struct tag.
+// The default, used when no mapper has been set, is CamelCaseNameMapper.
+//
+// **Parameters**
+//   - mapper: function used to convert Go field names to json property names
+//
+// **Returns**
+//   - *JSONWriter: this writer for fluent behavior
+func (writer *JSONWriter) SetNameMapper(mapper NameMapper) *JSONWriter {
+	writer.namemapper = mapper
+	return writer
+}
+
+// SetEmitNull controls how the reflection-based WriteItem/writeObject path treats nil pointer
+// and interface struct fields: when emit is true it writes an explicit json null for them,
+// matching WriteNullableProperty; when false (the default) it omits the property entirely,
+// matching WriteProperty. Nil slices and maps are not affected by this setting: they are
+// written through WriteItem as usual (a nil slice as "[]"). Fields tagged with
+// `json:",omitempty"` are always omitted regardless of this setting.
+//
+// **Parameters**
+//   - emit: true to emit explicit null for nil fields, false to omit them
+//
+// **Returns**
+//   - *JSONWriter: this writer for fluent behavior
+func (writer *JSONWriter) SetEmitNull(emit bool) *JSONWriter {
+	writer.emitnull = emit
+	return writer
+}
+
+// reset restores the writer to its initial, empty state so it can begin writing a new
+// top-level value as though freshly constructed. Configuration (indentation, name mapper,
+// null handling) is preserved. Used by JSONLinesWriter to emit one independent value per line.
+func (writer *JSONWriter) reset() {
+	writer.trailingseparator = false
+	writer.structure = 0
+	writer.depth = 0
+	writer.level = 0
+	writer.bracketopen = false
+}
+
+func (writer *JSONWriter) mapName(name string) string {
+	if writer.namemapper != nil {
+		return writer.namemapper(name)
+	}
+	return CamelCaseNameMapper(name)
+}
+
+func (writer *JSONWriter) toCamelCase(data string) string {
+	return CamelCaseNameMapper(data)
+}
+
+// jsonTag describes the result of parsing a field's `json:"..."` struct tag
+type jsonTag struct {
+	name      string
+	omitempty bool
+	skip      bool
+}
+
+func parseJSONTag(field reflect.StructField) jsonTag {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok || tag == "" {
+		return jsonTag{}
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] == "-" && len(parts) == 1 {
+		return jsonTag{skip: true}
+	}
+
+	result := jsonTag{name: parts[0]}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			result.omitempty = true
+		}
+	}
+	return result
+}
+
+// isEmptyValue reports whether value is the zero value of its kind, mirroring the notion of
+// "empty" used by encoding/json's omitempty struct tag option
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return false
+}
+
+// isNilField reports whether value is a nil pointer or interface, matching what writeObject
+// has always skipped by default. Nil slices and maps are deliberately excluded: they continue
+// to render through WriteItem ("[]"/omitted per the existing Map/Chan handling there), since
+// treating every nilable kind as implicitly omitted would collapse the distinction between an
+// absent property and one explicitly written as empty.
+func isNilField(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
 }
 
 func (writer *JSONWriter) writeObject(item interface{}) {
@@ -260,13 +505,34 @@ func (writer *JSONWriter) writeObject(item interface{}) {
 	}
 
 	for i := 0; i < typeinfo.NumField(); i++ {
+		field := typeinfo.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported field
+		}
+
+		tag := parseJSONTag(field)
+		if tag.skip {
+			continue
+		}
+
 		fieldvalue := typevalue.Field(i)
-		if fieldvalue.Kind() == reflect.Ptr && fieldvalue.IsNil() {
+		if tag.omitempty && isEmptyValue(fieldvalue) {
 			continue
 		}
 
-		field := typeinfo.Field(i)
-		writer.WriteProperty(writer.toCamelCase(field.Name), fieldvalue.Interface())
+		name := tag.name
+		if name == "" {
+			name = writer.mapName(field.Name)
+		}
+
+		if isNilField(fieldvalue) {
+			if writer.emitnull {
+				writer.WriteNullableProperty(name, nil)
+			}
+			continue
+		}
+
+		writer.WriteProperty(name, fieldvalue.Interface())
 	}
 	writer.EndObject()
 }
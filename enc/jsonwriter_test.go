@@ -113,6 +113,87 @@ func TestWriteObjectItem(t *testing.T) {
 	assert.Equal(t, `{"test":{"name":"Test","number":8}}`, buffer.String())
 }
 
+func TestWriteIndentedJson(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+	writer.SetIndent("", "  ")
+
+	writer.BeginObject()
+	writer.WriteProperty("firstname", "Diether")
+	writer.WriteKey("contact")
+	writer.BeginObject()
+	writer.WriteProperty("email", "d.boffel@fims.it")
+	writer.EndObject()
+	writer.EndObject()
+
+	assert.Equal(t, "{\n  \"firstname\": \"Diether\",\n  \"contact\": {\n    \"email\": \"d.boffel@fims.it\"\n  }\n}", buffer.String())
+}
+
+func TestWriteIndentedEmptyObject(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+	writer.SetIndent("", "  ")
+
+	writer.BeginObject()
+	writer.EndObject()
+
+	assert.Equal(t, `{}`, buffer.String())
+}
+
+type TaggedTestData struct {
+	Name     string `json:"fullName"`
+	Password string `json:"-"`
+	Optional string `json:"optional,omitempty"`
+	Internal string
+}
+
+func TestWriteObjectHonorsJsonTags(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+
+	writer.WriteItem(&TaggedTestData{
+		Name:     "Karl",
+		Password: "secret",
+		Internal: "kept"})
+
+	assert.Equal(t, `{"fullName":"Karl","internal":"kept"}`, buffer.String())
+}
+
+func TestWriteObjectWithSnakeCaseNameMapper(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+	writer.SetNameMapper(SnakeCaseNameMapper)
+
+	writer.WriteItem(&TestData{
+		Name:   "Test",
+		Number: 8})
+
+	assert.Equal(t, `{"name":"Test","number":8}`, buffer.String())
+}
+
+func TestWriteObjectWithEmitNull(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+	writer.SetEmitNull(true)
+
+	writer.WriteItem(&TestData{
+		Name:   "Test",
+		Number: 8})
+
+	assert.Equal(t, `{"name":"Test","number":8,"data":null}`, buffer.String())
+}
+
+func TestWriteNullablePropertyEmitsNull(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+
+	writer.BeginObject()
+	writer.WriteNullableProperty("data", nil)
+	writer.EndObject()
+
+	assert.Equal(t, `{"data":null}`, buffer.String())
+}
+
 func TestWriteObjectItemWithSubObjects(t *testing.T) {
 	var buffer bytes.Buffer
 	writer := NewJSONWriter(&buffer)
@@ -129,3 +210,35 @@ func TestWriteObjectItemWithSubObjects(t *testing.T) {
 
 	assert.Equal(t, `{"test":{"name":"Test","number":8,"data":{"name":"SubTest","number":12}}}`, buffer.String())
 }
+
+func TestWriteControlCharacterEscapes(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+
+	writer.WriteItem("a\b\f\n\r\t\x01b")
+
+	assert.Equal(t, `"a\b\f\n\r\t\u0001b"`, buffer.String())
+}
+
+type NilSliceTestData struct {
+	Name string
+	Tags []string
+}
+
+func TestWriteObjectWithNilSliceFieldWritesEmptyArray(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+
+	writer.WriteItem(&NilSliceTestData{Name: "Test"})
+
+	assert.Equal(t, `{"name":"Test","tags":[]}`, buffer.String())
+}
+
+func TestWriteSurrogatePairEscape(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+
+	writer.WriteItem("\U0001F600")
+
+	assert.Equal(t, `"\ud83d\ude00"`, buffer.String())
+}
@@ -0,0 +1,122 @@
+package enc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Utf8FilterPolicy controls how Utf8FilterReader handles invalid utf8 byte sequences found in
+// its underlying stream
+type Utf8FilterPolicy int8
+
+const (
+	// Utf8FilterStrip drops invalid byte sequences from the stream entirely
+	Utf8FilterStrip Utf8FilterPolicy = iota
+
+	// Utf8FilterReplace substitutes invalid byte sequences with the unicode replacement
+	// character U+FFFD
+	Utf8FilterReplace
+
+	// Utf8FilterError causes Read to fail with an error as soon as an invalid byte sequence
+	// is encountered
+	Utf8FilterError
+)
+
+// Utf8FilterReader is a stream which filters invalid utf8 byte sequences out of an underlying
+// reader, decoding it rune by rune rather than inspecting individual bytes. Invalid sequences
+// are handled according to its Utf8FilterPolicy.
+type Utf8FilterReader struct {
+	reader  *bufio.Reader
+	policy  Utf8FilterPolicy
+	pending []byte // bytes of the most recently decoded rune, not yet returned from Read
+	err     error  // sticky error once Utf8FilterError has rejected a byte sequence
+}
+
+// NewUtf8FilterReader creates a new Utf8FilterReader which strips invalid utf8 byte
+// sequences from the stream
+//
+// **Parameters**
+//   - inputreader: source stream to filter
+//
+// **Returns**
+//   - *Utf8FilterReader: created reader
+func NewUtf8FilterReader(inputreader io.Reader) *Utf8FilterReader {
+	return NewUtf8FilterReaderPolicy(inputreader, Utf8FilterStrip)
+}
+
+// NewUtf8FilterReaderPolicy creates a new Utf8FilterReader using the given policy to handle
+// invalid utf8 byte sequences
+//
+// **Parameters**
+//   - inputreader: source stream to filter
+//   - policy:      how to handle invalid utf8 byte sequences
+//
+// **Returns**
+//   - *Utf8FilterReader: created reader
+func NewUtf8FilterReaderPolicy(inputreader io.Reader, policy Utf8FilterPolicy) *Utf8FilterReader {
+	return &Utf8FilterReader{
+		reader: bufio.NewReader(inputreader),
+		policy: policy}
+}
+
+// Read reads data from the original reader, filtering out invalid utf8 byte sequences
+// according to the reader's policy
+//
+// **Parameters**
+//   - p: buffer to fill
+//
+// **Returns**
+//   - int: number of bytes read
+//   - error: error if any occurred, including an invalid sequence under Utf8FilterError
+func (stream *Utf8FilterReader) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(stream.pending) == 0 {
+			if err := stream.fill(); err != nil {
+				if n > 0 {
+					return n, nil
+				}
+				return 0, err
+			}
+		}
+
+		copied := copy(p[n:], stream.pending)
+		stream.pending = stream.pending[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+// fill decodes the next rune from the underlying reader into stream.pending, applying the
+// reader's policy whenever it encounters an invalid byte sequence
+func (stream *Utf8FilterReader) fill() error {
+	if stream.err != nil {
+		return stream.err
+	}
+
+	for {
+		char, size, err := stream.reader.ReadRune()
+		if err != nil {
+			return err
+		}
+
+		// bufio.Reader.ReadRune reports an invalid encoding by returning utf8.RuneError with
+		// size 1; a genuine, correctly encoded U+FFFD has size 3 and is left untouched
+		if char != utf8.RuneError || size > 1 {
+			stream.pending = []byte(string(char))
+			return nil
+		}
+
+		switch stream.policy {
+		case Utf8FilterStrip:
+			continue
+		case Utf8FilterReplace:
+			stream.pending = []byte(string(utf8.RuneError))
+			return nil
+		default:
+			stream.err = fmt.Errorf("invalid utf8 byte sequence in input")
+			return stream.err
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package enc
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUtf8FilterReaderPassesValidUtf8(t *testing.T) {
+	reader := NewUtf8FilterReader(strings.NewReader("héllo wörld"))
+
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "héllo wörld", string(data))
+}
+
+func TestUtf8FilterReaderStripsInvalidSequences(t *testing.T) {
+	reader := NewUtf8FilterReader(strings.NewReader("a\xffb"))
+
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", string(data))
+}
+
+func TestUtf8FilterReaderReplacesInvalidSequences(t *testing.T) {
+	reader := NewUtf8FilterReaderPolicy(strings.NewReader("a\xffb"), Utf8FilterReplace)
+
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "a�b", string(data))
+}
+
+func TestUtf8FilterReaderErrorsOnInvalidSequences(t *testing.T) {
+	reader := NewUtf8FilterReaderPolicy(strings.NewReader("a\xffb"), Utf8FilterError)
+
+	_, err := io.ReadAll(reader)
+	assert.Error(t, err)
+}
@@ -0,0 +1,423 @@
+package enc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// XMLEventType describes the kind of token reported by XMLReader.Peek
+type XMLEventType int8
+
+const (
+	// XMLEventNone signals the end of the document
+	XMLEventNone XMLEventType = iota
+
+	// XMLEventBeginTag signals the start of an element
+	XMLEventBeginTag
+
+	// XMLEventEndTag signals the end of an element
+	XMLEventEndTag
+
+	// XMLEventContent signals character data
+	XMLEventContent
+)
+
+// XMLReader reads xml data from a reader in a pull/streaming fashion, mirroring the
+// structural events produced by XMLWriter, built on top of encoding/xml's tokenizer.
+type XMLReader struct {
+	decoder    *xml.Decoder
+	tags       []string
+	current    xml.StartElement
+	pending    xml.Token
+	haspending bool
+}
+
+// NewXMLReader creates a new XMLReader
+//
+// **Parameters**
+//   - reader: reader to read xml data from
+//
+// **Returns**
+//   - *XMLReader: created XMLReader
+func NewXMLReader(reader io.Reader) *XMLReader {
+	return &XMLReader{decoder: xml.NewDecoder(reader)}
+}
+
+func (reader *XMLReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s (offset %d)", fmt.Sprintf(format, args...), reader.decoder.InputOffset())
+}
+
+// nextToken returns the next structurally significant token, skipping comments, processing
+// instructions and directives, which this reader does not surface
+func (reader *XMLReader) nextToken() (xml.Token, error) {
+	if reader.haspending {
+		reader.haspending = false
+		return reader.pending, nil
+	}
+
+	for {
+		token, err := reader.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token.(type) {
+		case xml.Comment, xml.ProcInst, xml.Directive:
+			continue
+		}
+		return xml.CopyToken(token), nil
+	}
+}
+
+func (reader *XMLReader) peekToken() (xml.Token, error) {
+	if !reader.haspending {
+		token, err := reader.nextToken()
+		if err != nil {
+			return nil, err
+		}
+
+		reader.pending = token
+		reader.haspending = true
+	}
+
+	return reader.pending, nil
+}
+
+// Peek reports the XMLEventType of the upcoming token without consuming it
+//
+// **Returns**
+//   - XMLEventType: kind of event that comes next
+//   - error: error if any occurred
+func (reader *XMLReader) Peek() (XMLEventType, error) {
+	token, err := reader.peekToken()
+	if err != nil {
+		if err == io.EOF {
+			return XMLEventNone, nil
+		}
+		return XMLEventNone, reader.errorf("%s", err.Error())
+	}
+
+	switch token.(type) {
+	case xml.StartElement:
+		return XMLEventBeginTag, nil
+	case xml.EndElement:
+		return XMLEventEndTag, nil
+	case xml.CharData:
+		return XMLEventContent, nil
+	}
+	return XMLEventNone, nil
+}
+
+// BeginTag reads the next token, which must be the start of an element
+//
+// **Returns**
+//   - string: local name of the element that was started
+//   - error: error if the next token is not the start of an element
+func (reader *XMLReader) BeginTag() (string, error) {
+	token, err := reader.nextToken()
+	if err != nil {
+		return "", reader.errorf("%s", err.Error())
+	}
+
+	start, ok := token.(xml.StartElement)
+	if !ok {
+		return "", reader.errorf("expected start of an element, found %v", token)
+	}
+
+	reader.tags = append(reader.tags, start.Name.Local)
+	reader.current = start
+	return start.Name.Local, nil
+}
+
+// EndTag reads the next token, which must be the end of the currently open element
+//
+// **Returns**
+//   - error: error if the reader is not inside an element, or the next token is not its end
+func (reader *XMLReader) EndTag() error {
+	if len(reader.tags) == 0 {
+		return reader.errorf("not currently inside an element")
+	}
+
+	token, err := reader.nextToken()
+	if err != nil {
+		return reader.errorf("%s", err.Error())
+	}
+
+	end, ok := token.(xml.EndElement)
+	if !ok {
+		return reader.errorf("expected end of element, found %v", token)
+	}
+
+	expected := reader.tags[len(reader.tags)-1]
+	if end.Name.Local != expected {
+		return reader.errorf("expected </%s>, found </%s>", expected, end.Name.Local)
+	}
+
+	reader.tags = reader.tags[:len(reader.tags)-1]
+	return nil
+}
+
+// Attribute retrieves an attribute by local name from the element most recently started with
+// BeginTag
+//
+// **Parameters**
+//   - name: local name of the attribute to retrieve
+//
+// **Returns**
+//   - string: value of the attribute
+//   - bool: true if the attribute was present
+func (reader *XMLReader) Attribute(name string) (string, bool) {
+	for _, attribute := range reader.current.Attr {
+		if attribute.Name.Local == name {
+			return attribute.Value, true
+		}
+	}
+	return "", false
+}
+
+// Attributes returns every attribute of the element most recently started with BeginTag
+//
+// **Returns**
+//   - []xml.Attr: attributes of the current element
+func (reader *XMLReader) Attributes() []xml.Attr {
+	return reader.current.Attr
+}
+
+// Content reads the next character data token
+//
+// **Returns**
+//   - string: character data that was read
+//   - error: error if the next token is not character data
+func (reader *XMLReader) Content() (string, error) {
+	token, err := reader.nextToken()
+	if err != nil {
+		return "", reader.errorf("%s", err.Error())
+	}
+
+	data, ok := token.(xml.CharData)
+	if !ok {
+		return "", reader.errorf("expected character data, found %v", token)
+	}
+	return string(data), nil
+}
+
+// skipElement discards the body of an already-begun element, leaving its matching end tag
+// unconsumed so the caller can read it the same way it would for any other child element
+func (reader *XMLReader) skipElement() error {
+	depth := 0
+	for {
+		event, err := reader.Peek()
+		if err != nil {
+			return err
+		}
+
+		switch event {
+		case XMLEventBeginTag:
+			if _, err := reader.BeginTag(); err != nil {
+				return err
+			}
+			depth++
+		case XMLEventEndTag:
+			if depth == 0 {
+				return nil
+			}
+			if err := reader.EndTag(); err != nil {
+				return err
+			}
+			depth--
+		case XMLEventContent:
+			if _, err := reader.Content(); err != nil {
+				return err
+			}
+		case XMLEventNone:
+			return reader.errorf("unexpected end of document while skipping an element")
+		}
+	}
+}
+
+type xmlFieldInfo struct {
+	index int
+	attr  bool
+}
+
+func xmlFieldsByTag(t reflect.Type) map[string]xmlFieldInfo {
+	fields := make(map[string]xmlFieldInfo, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Name == "XMLName" {
+			continue
+		}
+
+		tag := parseXMLTag(field)
+		if tag.skip {
+			continue
+		}
+
+		fields[tag.name] = xmlFieldInfo{index: i, attr: tag.attr}
+	}
+	return fields
+}
+
+func assignString(target reflect.Value, value string) error {
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		target.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		target.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(parsed)
+	}
+	return nil
+}
+
+// unmarshalChild decodes an already-begun child element into the appropriate field/entry of
+// target, a struct or map
+func (reader *XMLReader) unmarshalChild(target reflect.Value, fields map[string]xmlFieldInfo, name string) error {
+	switch target.Kind() {
+	case reflect.Struct:
+		info, ok := fields[name]
+		if !ok {
+			return reader.skipElement()
+		}
+
+		field := target.Field(info.index)
+		if field.Kind() == reflect.Slice {
+			element := reflect.New(field.Type().Elem()).Elem()
+			if err := reader.unmarshalElement(element); err != nil {
+				return err
+			}
+			field.Set(reflect.Append(field, element))
+			return nil
+		}
+		return reader.unmarshalElement(field)
+	case reflect.Map:
+		element := reflect.New(target.Type().Elem()).Elem()
+		if err := reader.unmarshalElement(element); err != nil {
+			return err
+		}
+		target.SetMapIndex(reflect.ValueOf(name), element)
+		return nil
+	default:
+		return reader.skipElement()
+	}
+}
+
+// unmarshalElement decodes the body of the element most recently started with BeginTag into
+// target, up to but not including its matching EndTag
+func (reader *XMLReader) unmarshalElement(target reflect.Value) error {
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	var fields map[string]xmlFieldInfo
+	switch target.Kind() {
+	case reflect.Struct:
+		fields = xmlFieldsByTag(target.Type())
+		for name, info := range fields {
+			if !info.attr {
+				continue
+			}
+			if value, ok := reader.Attribute(name); ok {
+				if err := assignString(target.Field(info.index), value); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Map:
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+	}
+
+	var text strings.Builder
+	for {
+		event, err := reader.Peek()
+		if err != nil {
+			return err
+		}
+
+		switch event {
+		case XMLEventEndTag, XMLEventNone:
+			if target.Kind() != reflect.Struct && target.Kind() != reflect.Map {
+				return assignString(target, text.String())
+			}
+			return nil
+		case XMLEventContent:
+			content, err := reader.Content()
+			if err != nil {
+				return err
+			}
+			text.WriteString(content)
+		case XMLEventBeginTag:
+			name, err := reader.BeginTag()
+			if err != nil {
+				return err
+			}
+
+			if err := reader.unmarshalChild(target, fields, name); err != nil {
+				return err
+			}
+
+			if err := reader.EndTag(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Unmarshal reads a single xml element from the stream into v, which must be a non-nil
+// pointer to a struct or map, symmetric with what XMLWriter.WriteItem writes: `xml:"name"`
+// struct tags name child elements, `xml:"name,attr"` reads an attribute instead, and a
+// repeated child element populates a slice field. Elements with no matching field are
+// skipped. The text content of an element decoded into a struct is discarded: a field tagged
+// `xml:",chardata"` is matched like any other child element by name and is not filled from
+// that text.
+//
+// **Parameters**
+//   - v: pointer to the value to decode into
+//
+// **Returns**
+//   - error: error if any occurred, including malformed input
+func (reader *XMLReader) Unmarshal(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return reader.errorf("Unmarshal requires a non-nil pointer, got %T", v)
+	}
+
+	if _, err := reader.BeginTag(); err != nil {
+		return err
+	}
+
+	if err := reader.unmarshalElement(value.Elem()); err != nil {
+		return err
+	}
+
+	return reader.EndTag()
+}
@@ -0,0 +1,105 @@
+package enc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadValidXML(t *testing.T) {
+	reader := NewXMLReader(strings.NewReader(`<root name="karl">Suffer</root>`))
+
+	name, err := reader.BeginTag()
+	assert.NoError(t, err)
+	assert.Equal(t, "root", name)
+
+	value, ok := reader.Attribute("name")
+	assert.True(t, ok)
+	assert.Equal(t, "karl", value)
+
+	content, err := reader.Content()
+	assert.NoError(t, err)
+	assert.Equal(t, "Suffer", content)
+
+	assert.NoError(t, reader.EndTag())
+}
+
+func TestPeekReportsUpcomingEvent(t *testing.T) {
+	reader := NewXMLReader(strings.NewReader(`<root><child/></root>`))
+
+	event, err := reader.Peek()
+	assert.NoError(t, err)
+	assert.Equal(t, XMLEventBeginTag, event)
+
+	_, err = reader.BeginTag()
+	assert.NoError(t, err)
+
+	event, err = reader.Peek()
+	assert.NoError(t, err)
+	assert.Equal(t, XMLEventBeginTag, event)
+
+	name, err := reader.BeginTag()
+	assert.NoError(t, err)
+	assert.Equal(t, "child", name)
+	assert.NoError(t, reader.EndTag())
+
+	event, err = reader.Peek()
+	assert.NoError(t, err)
+	assert.Equal(t, XMLEventEndTag, event)
+	assert.NoError(t, reader.EndTag())
+}
+
+func TestEndTagWithoutOpenTagFails(t *testing.T) {
+	reader := NewXMLReader(strings.NewReader(`<root/>`))
+	err := reader.EndTag()
+	assert.Error(t, err)
+}
+
+type xmlReaderTestAddress struct {
+	City string `xml:"city"`
+}
+
+type xmlReaderTestPerson struct {
+	Name   string               `xml:"name"`
+	Age    int                  `xml:"age"`
+	Active bool                 `xml:"active,attr"`
+	Tags   []string             `xml:"tag"`
+	Home   xmlReaderTestAddress `xml:"home"`
+}
+
+func TestUnmarshalIntoStruct(t *testing.T) {
+	reader := NewXMLReader(strings.NewReader(`<person active="true"><name>Karl</name><age>40</age><tag>a</tag><tag>b</tag><home><city>Berlin</city></home><unknown>skip me</unknown></person>`))
+
+	var person xmlReaderTestPerson
+	err := reader.Unmarshal(&person)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Karl", person.Name)
+	assert.Equal(t, 40, person.Age)
+	assert.True(t, person.Active)
+	assert.Equal(t, []string{"a", "b"}, person.Tags)
+	assert.Equal(t, "Berlin", person.Home.City)
+}
+
+func TestWriteItemUnmarshalRoundTrip(t *testing.T) {
+	var buffer strings.Builder
+	writer := NewXMLWriter(&buffer)
+	writer.WriteItem(&xmlReaderTestPerson{
+		Name:   "Karl",
+		Age:    40,
+		Active: true,
+		Tags:   []string{"a", "b"},
+		Home:   xmlReaderTestAddress{City: "Berlin"}})
+
+	reader := NewXMLReader(strings.NewReader(buffer.String()))
+	var person xmlReaderTestPerson
+	err := reader.Unmarshal(&person)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Karl", person.Name)
+	assert.Equal(t, 40, person.Age)
+	assert.True(t, person.Active)
+	assert.Equal(t, []string{"a", "b"}, person.Tags)
+	assert.Equal(t, "Berlin", person.Home.City)
+}
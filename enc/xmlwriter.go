@@ -4,13 +4,33 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"reflect"
+	"strings"
 )
 
+// xmlContentReplacer escapes the characters that are not valid as-is in xml text content
+var xmlContentReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;")
+
+// xmlAttributeReplacer escapes the characters that are not valid as-is in an xml attribute
+// value, in addition to those escaped by xmlContentReplacer
+var xmlAttributeReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;")
+
 // XMLWriter writes xml data to a writer
 type XMLWriter struct {
-	writer      io.Writer // writer used to write xml data to
-	trailingtag bool      // indicator whether a start tag is still open
-	tags        []string  // collection of open tags
+	writer       io.Writer // writer used to write xml data to
+	trailingtag  bool      // indicator whether a start tag is still open
+	tags         []string  // collection of open tags
+	haschildtag  []bool    // per open tag, whether a child tag has been written to it
+	depth        int       // number of currently open tags, used for indentation
+	indentprefix string    // prefix written at the start of every indented line
+	indentstring string    // string repeated once per nesting level to indent a line
 }
 
 // NewXMLWriter creates a new XMLWriter
@@ -24,7 +44,41 @@ func NewXMLWriter(writer io.Writer) *XMLWriter {
 	return &XMLWriter{
 		writer:      writer,
 		trailingtag: false,
-		tags:        make([]string, 8)}
+		tags:        make([]string, 8),
+		haschildtag: make([]bool, 8)}
+}
+
+// SetIndent instructs the writer to pretty-print its output: every nested start tag begins on
+// a new line, prefixed with prefix and indented by one copy of indent per nesting level. A
+// closing tag gets a line of its own only if its element has child tags; an element holding
+// just content stays on one line (e.g. `<child>hello</child>`). Passing empty strings for both
+// arguments (the default) restores minified, single-line output.
+//
+// **Parameters**
+//   - prefix: string written at the beginning of each line
+//   - indent: string repeated per nesting level to indent that line
+//
+// **Returns**
+//   - *XMLWriter: this writer for fluent behavior
+func (writer *XMLWriter) SetIndent(prefix string, indent string) *XMLWriter {
+	writer.indentprefix = prefix
+	writer.indentstring = indent
+	return writer
+}
+
+// pretty determines whether indented output was requested via SetIndent
+func (writer *XMLWriter) pretty() bool {
+	return writer.indentprefix != "" || writer.indentstring != ""
+}
+
+// writeIndent writes a newline followed by the configured prefix and indentation for the
+// given nesting level. It is a no-op unless SetIndent was used to enable pretty-printing.
+func (writer *XMLWriter) writeIndent(level int) {
+	if !writer.pretty() {
+		return
+	}
+
+	io.WriteString(writer.writer, "\n"+writer.indentprefix+strings.Repeat(writer.indentstring, level))
 }
 
 func (writer *XMLWriter) checkTrailingTag() {
@@ -42,10 +96,20 @@ func (writer *XMLWriter) checkTrailingTag() {
 // **Returns**
 //   - *XMLWriter: this writer for fluent behavior
 func (writer *XMLWriter) BeginTag(name string) *XMLWriter {
+	nested := writer.depth > 0
+	if nested {
+		writer.haschildtag[len(writer.haschildtag)-1] = true
+	}
+
 	writer.checkTrailingTag()
+	if nested {
+		writer.writeIndent(writer.depth)
+	}
 
 	io.WriteString(writer.writer, fmt.Sprintf("<%s", name))
 	writer.tags = append(writer.tags, name)
+	writer.haschildtag = append(writer.haschildtag, false)
+	writer.depth++
 	writer.trailingtag = true
 	return writer
 }
@@ -61,15 +125,20 @@ func (writer *XMLWriter) CloseTag() *XMLWriter {
 		return writer
 	}
 
+	writer.depth--
 	if writer.trailingtag {
 		io.WriteString(writer.writer, "/>")
 		writer.trailingtag = false
 	} else {
+		if writer.haschildtag[length-1] {
+			writer.writeIndent(writer.depth)
+		}
 		tagname := writer.tags[length-1]
 		io.WriteString(writer.writer, fmt.Sprintf("</%s>", tagname))
 	}
 
 	writer.tags = writer.tags[:length-1]
+	writer.haschildtag = writer.haschildtag[:length-1]
 	return writer
 }
 
@@ -87,7 +156,7 @@ func (writer *XMLWriter) WriteAttribute(key string, value string) *XMLWriter {
 		return writer
 	}
 
-	io.WriteString(writer.writer, fmt.Sprintf(" %s=\"%s\"", key, value))
+	io.WriteString(writer.writer, fmt.Sprintf(" %s=\"%s\"", key, xmlAttributeReplacer.Replace(value)))
 	return writer
 }
 
@@ -101,6 +170,238 @@ func (writer *XMLWriter) WriteAttribute(key string, value string) *XMLWriter {
 func (writer *XMLWriter) WriteContent(content string) *XMLWriter {
 	writer.checkTrailingTag()
 
-	io.WriteString(writer.writer, content)
+	io.WriteString(writer.writer, xmlContentReplacer.Replace(content))
+	return writer
+}
+
+// WriteDeclaration writes an xml declaration (e.g. `<?xml version="1.0" encoding="UTF-8"?>`).
+// Typically called once, before the document's root tag.
+//
+// **Parameters**
+//   - version:  xml version to declare, e.g. "1.0"
+//   - encoding: document encoding to declare, e.g. "UTF-8"
+//
+// **Returns**
+//   - *XMLWriter: this writer for fluent behavior
+func (writer *XMLWriter) WriteDeclaration(version string, encoding string) *XMLWriter {
+	io.WriteString(writer.writer, fmt.Sprintf(`<?xml version="%s" encoding="%s"?>`, version, encoding))
+	return writer
+}
+
+// WriteDoctype writes a DOCTYPE declaration (e.g. `<!DOCTYPE html>`). Typically called once,
+// after WriteDeclaration (if any) and before the document's root tag.
+//
+// **Parameters**
+//   - doctype: doctype content to write between "<!DOCTYPE " and ">"
+//
+// **Returns**
+//   - *XMLWriter: this writer for fluent behavior
+func (writer *XMLWriter) WriteDoctype(doctype string) *XMLWriter {
+	io.WriteString(writer.writer, fmt.Sprintf("<!DOCTYPE %s>", doctype))
+	return writer
+}
+
+// xmlTag describes the result of parsing a field's `xml:"..."` struct tag. Namespaces are not
+// currently supported: a tag's name is always written/matched as a plain local name, never
+// prefixed or qualified by a namespace URI, unlike encoding/xml's `xml:"uri name"` tag form.
+type xmlTag struct {
+	name      string
+	attr      bool
+	chardata  bool
+	omitempty bool
+	skip      bool
+}
+
+func parseXMLTag(field reflect.StructField) xmlTag {
+	tag, ok := field.Tag.Lookup("xml")
+	if !ok || tag == "" {
+		return xmlTag{name: field.Name}
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] == "-" && len(parts) == 1 {
+		return xmlTag{skip: true}
+	}
+
+	result := xmlTag{name: parts[0]}
+	if result.name == "" {
+		result.name = field.Name
+	}
+
+	for _, option := range parts[1:] {
+		switch option {
+		case "attr":
+			result.attr = true
+		case "chardata":
+			result.chardata = true
+		case "omitempty":
+			result.omitempty = true
+		}
+	}
+	return result
+}
+
+// xmlElementName determines the tag name to use for the root element written by
+// XMLWriter.WriteItem: the name of an `XMLName` field's xml tag if present, otherwise the
+// unqualified Go type name, mirroring encoding/xml's own Marshal conventions.
+func xmlElementName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct {
+		if field, ok := t.FieldByName("XMLName"); ok {
+			tag := parseXMLTag(field)
+			if tag.name != "" && tag.name != "XMLName" {
+				return tag.name
+			}
+		}
+	}
+
+	return t.Name()
+}
+
+func (writer *XMLWriter) writeStructFields(value reflect.Value) {
+	t := value.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Name == "XMLName" {
+			continue
+		}
+
+		tag := parseXMLTag(field)
+		if tag.skip || !tag.attr {
+			continue
+		}
+
+		fieldvalue := value.Field(i)
+		if tag.omitempty && isEmptyValue(fieldvalue) {
+			continue
+		}
+
+		writer.WriteAttribute(tag.name, fmt.Sprintf("%v", fieldvalue.Interface()))
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Name == "XMLName" {
+			continue
+		}
+
+		tag := parseXMLTag(field)
+		if tag.skip || tag.attr {
+			continue
+		}
+
+		fieldvalue := value.Field(i)
+		if tag.omitempty && isEmptyValue(fieldvalue) {
+			continue
+		}
+
+		if tag.chardata {
+			writer.WriteContent(fmt.Sprintf("%v", fieldvalue.Interface()))
+			continue
+		}
+
+		switch fieldvalue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < fieldvalue.Len(); i++ {
+				writer.writeElement(tag.name, fieldvalue.Index(i))
+			}
+		default:
+			writer.writeElement(tag.name, fieldvalue)
+		}
+	}
+}
+
+func (writer *XMLWriter) writeMapFields(value reflect.Value) {
+	for _, key := range value.MapKeys() {
+		writer.writeElement(fmt.Sprintf("%v", key.Interface()), value.MapIndex(key))
+	}
+}
+
+// writeElement writes value as a tag named name, recursing into structs and maps and
+// rendering everything else as text content
+func (writer *XMLWriter) writeElement(name string, value reflect.Value) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	writer.BeginTag(name)
+	switch value.Kind() {
+	case reflect.Struct:
+		writer.writeStructFields(value)
+	case reflect.Map:
+		writer.writeMapFields(value)
+	default:
+		writer.WriteContent(fmt.Sprintf("%v", value.Interface()))
+	}
+	writer.CloseTag()
+}
+
+// WriteItem serializes v, a struct, named slice/map type, or pointer to one, as xml:
+// `xml:"name,attr"` struct tags write attributes, `xml:"name,omitempty"` omits zero-valued
+// fields, `xml:"-"` skips a field entirely, and slice/array fields are written as one repeated
+// child element per entry. The root tag is named after an `XMLName` field's xml tag if v is a
+// struct, otherwise after v's own Go type name, mirroring encoding/xml's own Marshal
+// conventions. v's type must have a name to write as a tag this way; an anonymous slice or map
+// type (e.g. a literal []Person or map[string]string) has none, so WriteItem panics for those —
+// use WriteItemAs to supply the root element name explicitly.
+//
+// **Parameters**
+//   - v: value to serialize
+//
+// **Returns**
+//   - *XMLWriter: this writer for fluent behavior
+func (writer *XMLWriter) WriteItem(v interface{}) *XMLWriter {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return writer
+		}
+		value = value.Elem()
+	}
+
+	name := xmlElementName(value.Type())
+	if name == "" {
+		panic(fmt.Sprintf("XMLWriter.WriteItem cannot derive a root element name for %s; use WriteItemAs to supply one explicitly", value.Type()))
+	}
+
+	return writer.WriteItemAs(name, value.Interface())
+}
+
+// WriteItemAs serializes v, a struct, map, slice/array or pointer to one, as xml under the
+// given root element name, following the same struct-tag rules as WriteItem. A top-level slice
+// or array is written as one repeated element named name, the same way writeStructFields
+// handles a slice-typed struct field — use this instead of WriteItem whenever v's type has no
+// usable Go type name to derive a root tag from.
+//
+// **Parameters**
+//   - name: tag name to write the element(s) as
+//   - v:    value to serialize
+//
+// **Returns**
+//   - *XMLWriter: this writer for fluent behavior
+func (writer *XMLWriter) WriteItemAs(name string, v interface{}) *XMLWriter {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return writer
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			writer.writeElement(name, value.Index(i))
+		}
+	default:
+		writer.writeElement(name, value)
+	}
 	return writer
 }
@@ -19,3 +19,117 @@ func TestWriteValidXML(t *testing.T) {
 
 	assert.Equal(t, `<root name="karl" other="some">Suffer</root>`, buffer.String())
 }
+
+func TestWriteIndentedXML(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+	writer.SetIndent("", "  ")
+
+	writer.BeginTag("root")
+	writer.WriteAttribute("name", "karl")
+	writer.BeginTag("child")
+	writer.WriteContent("hello")
+	writer.CloseTag()
+	writer.CloseTag()
+
+	assert.Equal(t, "<root name=\"karl\">\n  <child>hello</child>\n</root>", buffer.String())
+}
+
+func TestWriteAttributeEscapesValue(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	writer.BeginTag("root")
+	writer.WriteAttribute("name", `<"Karl" & "Sons">`)
+	writer.CloseTag()
+
+	assert.Equal(t, `<root name="&lt;&quot;Karl&quot; &amp; &quot;Sons&quot;&gt;"/>`, buffer.String())
+}
+
+func TestWriteContentEscapesValue(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	writer.BeginTag("root")
+	writer.WriteContent("Tom & Jerry <3")
+	writer.CloseTag()
+
+	assert.Equal(t, `<root>Tom &amp; Jerry &lt;3</root>`, buffer.String())
+}
+
+func TestWriteDeclarationAndDoctype(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	writer.WriteDeclaration("1.0", "UTF-8")
+	writer.WriteDoctype("html")
+	writer.BeginTag("html")
+	writer.CloseTag()
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?><!DOCTYPE html><html/>`, buffer.String())
+}
+
+type xmlTestAddress struct {
+	City string `xml:"city"`
+}
+
+type xmlTestPerson struct {
+	Name   string         `xml:"name"`
+	Age    int            `xml:"age"`
+	Active bool           `xml:"active,attr"`
+	Tags   []string       `xml:"tag"`
+	Home   xmlTestAddress `xml:"home"`
+	Secret string         `xml:"-"`
+	Note   string         `xml:"note,omitempty"`
+}
+
+func TestWriteItemSerializesStruct(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	writer.WriteItem(&xmlTestPerson{
+		Name:   "Karl",
+		Age:    40,
+		Active: true,
+		Tags:   []string{"a", "b"},
+		Home:   xmlTestAddress{City: "Berlin"},
+		Secret: "hidden"})
+
+	assert.Equal(t, `<xmlTestPerson active="true"><name>Karl</name><age>40</age><tag>a</tag><tag>b</tag><home><city>Berlin</city></home></xmlTestPerson>`, buffer.String())
+}
+
+func TestWriteItemOnAnonymousSliceTypePanics(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	assertPanic(t, func() {
+		writer.WriteItem([]xmlTestAddress{{City: "Berlin"}})
+	})
+}
+
+func TestWriteItemOnAnonymousMapTypePanics(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	assertPanic(t, func() {
+		writer.WriteItem(map[string]string{"a": "1"})
+	})
+}
+
+func TestWriteItemAsSerializesTopLevelSlice(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	writer.WriteItemAs("address", []xmlTestAddress{{City: "Berlin"}, {City: "Paris"}})
+
+	assert.Equal(t, `<address><city>Berlin</city></address><address><city>Paris</city></address>`, buffer.String())
+}
+
+func TestWriteItemAsSerializesTopLevelMap(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewXMLWriter(&buffer)
+
+	writer.WriteItemAs("root", map[string]string{"a": "1"})
+
+	assert.Equal(t, `<root><a>1</a></root>`, buffer.String())
+}